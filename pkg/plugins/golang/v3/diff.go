@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines of context kept around each hunk,
+// matching the default of `diff -u`/git.
+const diffContextLines = 3
+
+// diffOpKind is the unified-diff marker for a line: ' ' (context), '-' (removed) or
+// '+' (added).
+type diffOpKind byte
+
+const (
+	diffEqual  diffOpKind = ' '
+	diffDelete diffOpKind = '-'
+	diffInsert diffOpKind = '+'
+)
+
+type diffOp struct {
+	kind diffOpKind
+	line string
+}
+
+// diffLines computes a minimal line-level edit script turning oldLines into newLines,
+// using the standard dynamic-programming LCS diff algorithm.
+func diffLines(oldLines, newLines []string) []diffOp {
+	n, m := len(oldLines), len(newLines)
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			switch {
+			case oldLines[i] == newLines[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case oldLines[i] == newLines[j]:
+			ops = append(ops, diffOp{diffEqual, oldLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{diffDelete, oldLines[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{diffInsert, newLines[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{diffDelete, oldLines[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{diffInsert, newLines[j]})
+	}
+
+	return ops
+}
+
+// hunk is a contiguous run of diffOps, plus surrounding context, to be printed under one
+// "@@ -oldStart,oldLines +newStart,newLines @@" header.
+type hunk struct {
+	oldStart, oldLines int
+	newStart, newLines int
+	ops                []diffOp
+}
+
+// computeHunks groups a flat edit script into unified-diff hunks: each run of changed
+// lines keeps diffContextLines of unchanged context on either side, and runs whose
+// context would overlap are merged into a single hunk.
+func computeHunks(ops []diffOp) []hunk {
+	oldPos := make([]int, len(ops)+1)
+	newPos := make([]int, len(ops)+1)
+	for i, op := range ops {
+		oldPos[i+1], newPos[i+1] = oldPos[i], newPos[i]
+		switch op.kind {
+		case diffEqual:
+			oldPos[i+1]++
+			newPos[i+1]++
+		case diffDelete:
+			oldPos[i+1]++
+		case diffInsert:
+			newPos[i+1]++
+		}
+	}
+
+	var ranges [][2]int
+	for i := 0; i < len(ops); {
+		if ops[i].kind == diffEqual {
+			i++
+			continue
+		}
+
+		changeStart := i
+		for i < len(ops) && ops[i].kind != diffEqual {
+			i++
+		}
+
+		rangeStart := changeStart - diffContextLines
+		if rangeStart < 0 {
+			rangeStart = 0
+		}
+		rangeEnd := i + diffContextLines
+		if rangeEnd > len(ops) {
+			rangeEnd = len(ops)
+		}
+
+		if len(ranges) > 0 && rangeStart <= ranges[len(ranges)-1][1] {
+			ranges[len(ranges)-1][1] = rangeEnd
+		} else {
+			ranges = append(ranges, [2]int{rangeStart, rangeEnd})
+		}
+	}
+
+	result := make([]hunk, 0, len(ranges))
+	for _, r := range ranges {
+		oldLines := oldPos[r[1]] - oldPos[r[0]]
+		newLines := newPos[r[1]] - newPos[r[0]]
+
+		// Unified-diff convention: a side with a zero line count (a pure insertion or
+		// pure deletion) reports its start as the 0-indexed position rather than the
+		// usual 1-indexed line number, e.g. "@@ -0,0 +1,3 @@" for a brand-new file.
+		oldStart := oldPos[r[0]] + 1
+		if oldLines == 0 {
+			oldStart = oldPos[r[0]]
+		}
+		newStart := newPos[r[0]] + 1
+		if newLines == 0 {
+			newStart = newPos[r[0]]
+		}
+
+		result = append(result, hunk{
+			oldStart: oldStart,
+			oldLines: oldLines,
+			newStart: newStart,
+			newLines: newLines,
+			ops:      ops[r[0]:r[1]],
+		})
+	}
+
+	return result
+}
+
+// formatHunk renders h in unified-diff form, e.g.:
+//
+//	@@ -1,3 +1,3 @@
+//	 unchanged
+//	-removed
+//	+added
+func formatHunk(h hunk) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", h.oldStart, h.oldLines, h.newStart, h.newLines)
+	for _, op := range h.ops {
+		fmt.Fprintf(&b, "%c%s\n", op.kind, op.line)
+	}
+	return b.String()
+}
+
+// splitLines splits content into lines. bytes.Split/strings.Split on content ending in a
+// newline (virtually all scaffolded files) would otherwise produce a trailing empty
+// element, which is dropped here so callers don't render a spurious blank final line.
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+
+	lines := strings.Split(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitLinesDropsTrailingNewline(t *testing.T) {
+	got := splitLines([]byte("a\nb\nc\n"))
+	want := []string{"a", "b", "c"}
+
+	if len(got) != len(want) {
+		t.Fatalf("splitLines() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("splitLines()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSplitLinesNoTrailingNewline(t *testing.T) {
+	got := splitLines([]byte("a\nb"))
+	want := []string{"a", "b"}
+
+	if len(got) != len(want) {
+		t.Fatalf("splitLines() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("splitLines()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSplitLinesEmpty(t *testing.T) {
+	if got := splitLines(nil); got != nil {
+		t.Errorf("splitLines(nil) = %v, want nil", got)
+	}
+}
+
+func TestComputeHunksOneLineChangeInLargeFile(t *testing.T) {
+	old := make([]string, 500)
+	updated := make([]string, 500)
+	for i := range old {
+		old[i] = "line"
+		updated[i] = "line"
+	}
+	updated[250] = "changed"
+
+	ops := diffLines(old, updated)
+	hunks := computeHunks(ops)
+
+	if len(hunks) != 1 {
+		t.Fatalf("expected exactly one hunk for a single changed line, got %d", len(hunks))
+	}
+
+	h := hunks[0]
+	// A single-line change should only carry diffContextLines of context on either
+	// side, not the whole 500-line file.
+	wantLines := 1 + 2*diffContextLines
+	if h.oldLines != wantLines || h.newLines != wantLines {
+		t.Errorf("hunk size = (%d,%d), want (%d,%d)", h.oldLines, h.newLines, wantLines, wantLines)
+	}
+
+	removed, added := 0, 0
+	for _, op := range h.ops {
+		switch op.kind {
+		case diffDelete:
+			removed++
+		case diffInsert:
+			added++
+		}
+	}
+	if removed != 1 || added != 1 {
+		t.Errorf("expected exactly one removed and one added line, got removed=%d added=%d", removed, added)
+	}
+}
+
+func TestComputeHunksNoOpWhenIdentical(t *testing.T) {
+	lines := []string{"a", "b", "c"}
+	ops := diffLines(lines, lines)
+	if hunks := computeHunks(ops); len(hunks) != 0 {
+		t.Errorf("expected no hunks for identical input, got %d", len(hunks))
+	}
+}
+
+func TestComputeHunksNewFileUsesZeroOldStart(t *testing.T) {
+	ops := diffLines(nil, []string{"a", "b", "c"})
+	hunks := computeHunks(ops)
+	if len(hunks) != 1 {
+		t.Fatalf("expected one hunk, got %d", len(hunks))
+	}
+
+	h := hunks[0]
+	if h.oldStart != 0 || h.oldLines != 0 {
+		t.Errorf("oldStart,oldLines = %d,%d, want 0,0 for a brand-new file", h.oldStart, h.oldLines)
+	}
+	if h.newStart != 1 || h.newLines != 3 {
+		t.Errorf("newStart,newLines = %d,%d, want 1,3", h.newStart, h.newLines)
+	}
+
+	got := formatHunk(h)
+	if !strings.HasPrefix(got, "@@ -0,0 +1,3 @@\n") {
+		t.Errorf("formatHunk() header = %q, want it to start with \"@@ -0,0 +1,3 @@\\n\"", got)
+	}
+}
+
+func TestFormatHunkRendersUnifiedDiffSyntax(t *testing.T) {
+	ops := diffLines([]string{"a", "b", "c"}, []string{"a", "x", "c"})
+	hunks := computeHunks(ops)
+	if len(hunks) != 1 {
+		t.Fatalf("expected one hunk, got %d", len(hunks))
+	}
+
+	got := formatHunk(hunks[0])
+	if !strings.HasPrefix(got, "@@ -1,3 +1,3 @@\n") {
+		t.Errorf("formatHunk() header = %q, want it to start with \"@@ -1,3 +1,3 @@\\n\"", got)
+	}
+	for _, want := range []string{" a\n", "-b\n", "+x\n", " c\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected formatted hunk to contain %q, got:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+)
+
+var _ machinery.Template = &Manifests{}
+
+// defaultManifestsFailurePolicy, defaultManifestsSideEffects, defaultManifestsTimeoutSeconds and
+// defaultManifestsAdmissionReviewVersions mirror the defaults in pkg/plugins/golang/v3/webhook.go's
+// BindFlags, applied here too so Manifests renders sane values when built directly (e.g. in tests).
+const (
+	defaultManifestsFailurePolicy  = "Fail"
+	defaultManifestsSideEffects    = "None"
+	defaultManifestsTimeoutSeconds = 10
+)
+
+var defaultManifestsAdmissionReviewVersions = []string{"v1"}
+
+// ManifestOptions are the admission webhook manifest fields a user can override when
+// scaffolding a webhook. It mirrors scaffolds.WebhookManifestOptions; kept as a distinct
+// type so this template package does not need to import the scaffolds package.
+type ManifestOptions struct {
+	// Path overrides the default /{mutate,validate}-<group>-<version>-<kind> route.
+	Path string
+	// FailurePolicy is the failurePolicy of the scaffolded webhook. Options: [Ignore, Fail].
+	FailurePolicy string
+	// SideEffects is the sideEffects of the scaffolded webhook. Options: [None, NoneOnDryRun, Some, Unknown].
+	SideEffects string
+	// TimeoutSeconds is the timeoutSeconds of the scaffolded webhook.
+	TimeoutSeconds int
+	// AdmissionReviewVersions is the admissionReviewVersions of the scaffolded webhook.
+	AdmissionReviewVersions []string
+}
+
+// Manifests scaffolds the kustomize patch that defines the {Mutating,Validating}WebhookConfigurations
+// for the resource's webhooks.
+type Manifests struct {
+	machinery.TemplateMixin
+	machinery.ResourceMixin
+
+	// Force if true, overwrites the file if it already exists.
+	Force bool
+
+	// Options carries the user-provided --webhook-path, --failure-policy, --side-effects,
+	// --timeout-seconds and --admission-review-versions values rendered into the manifest below.
+	Options ManifestOptions
+}
+
+// SetTemplateDefaults implements machinery.Template.
+func (f *Manifests) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "webhook", "manifests.yaml")
+	}
+
+	f.IfExistsAction = machinery.OverwriteFile
+	if !f.Force {
+		f.IfExistsAction = machinery.Error
+	}
+
+	if f.Options.FailurePolicy == "" {
+		f.Options.FailurePolicy = defaultManifestsFailurePolicy
+	}
+	if f.Options.SideEffects == "" {
+		f.Options.SideEffects = defaultManifestsSideEffects
+	}
+	if f.Options.TimeoutSeconds == 0 {
+		f.Options.TimeoutSeconds = defaultManifestsTimeoutSeconds
+	}
+	if len(f.Options.AdmissionReviewVersions) == 0 {
+		f.Options.AdmissionReviewVersions = defaultManifestsAdmissionReviewVersions
+	}
+
+	f.TemplateBody = manifestsTemplate
+
+	return nil
+}
+
+//nolint:lll
+const manifestsTemplate = `{{ if .Resource.HasDefaultingWebhook }}
+---
+apiVersion: admissionregistration.k8s.io/{{ .Resource.Webhooks.WebhookVersion }}
+kind: MutatingWebhookConfiguration
+metadata:
+  name: mutating-webhook-configuration
+webhooks:
+- admissionReviewVersions:
+  {{- range .Options.AdmissionReviewVersions }}
+  - {{ . }}
+  {{- end }}
+  clientConfig:
+    service:
+      name: webhook-service
+      namespace: system
+      path: {{ if .Options.Path }}{{ .Options.Path }}{{ else }}/mutate-{{ .Resource.Group }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }}{{ end }}
+  failurePolicy: {{ .Options.FailurePolicy }}
+  name: m{{ lower .Resource.Kind }}.kb.io
+  rules:
+  - apiGroups:
+    - {{ .Resource.Group }}
+    apiVersions:
+    - {{ .Resource.Version }}
+    operations:
+    - CREATE
+    - UPDATE
+    resources:
+    - {{ .Resource.Plural }}
+  sideEffects: {{ .Options.SideEffects }}
+  timeoutSeconds: {{ .Options.TimeoutSeconds }}
+{{ end }}
+{{ if .Resource.HasValidationWebhook }}
+---
+apiVersion: admissionregistration.k8s.io/{{ .Resource.Webhooks.WebhookVersion }}
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: validating-webhook-configuration
+webhooks:
+- admissionReviewVersions:
+  {{- range .Options.AdmissionReviewVersions }}
+  - {{ . }}
+  {{- end }}
+  clientConfig:
+    service:
+      name: webhook-service
+      namespace: system
+      path: {{ if .Options.Path }}{{ .Options.Path }}{{ else }}/validate-{{ .Resource.Group }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }}{{ end }}
+  failurePolicy: {{ .Options.FailurePolicy }}
+  name: v{{ lower .Resource.Kind }}.kb.io
+  rules:
+  - apiGroups:
+    - {{ .Resource.Group }}
+    apiVersions:
+    - {{ .Resource.Version }}
+    operations:
+    - CREATE
+    - UPDATE
+    resources:
+    - {{ .Resource.Plural }}
+  sideEffects: {{ .Options.SideEffects }}
+  timeoutSeconds: {{ .Options.TimeoutSeconds }}
+{{ end }}
+`
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// fakeWebhooks and fakeResource stand in for resource.Resource/resource.Webhooks: the
+// real types live outside this tree, but manifestsTemplate only ever touches the fields
+// and methods stubbed here, so this is enough to exercise the rendering logic directly.
+type fakeWebhooks struct {
+	WebhookVersion string
+}
+
+type fakeResource struct {
+	Group, Version, Kind, Plural string
+	Webhooks                     fakeWebhooks
+
+	defaulting, validating bool
+}
+
+func (r fakeResource) HasDefaultingWebhook() bool { return r.defaulting }
+func (r fakeResource) HasValidationWebhook() bool { return r.validating }
+
+type manifestsTemplateData struct {
+	Resource fakeResource
+	Options  ManifestOptions
+}
+
+func renderManifests(t *testing.T, data manifestsTemplateData) string {
+	t.Helper()
+
+	tmpl, err := template.New("manifests").Funcs(template.FuncMap{"lower": strings.ToLower}).Parse(manifestsTemplate)
+	if err != nil {
+		t.Fatalf("unable to parse manifestsTemplate: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		t.Fatalf("unable to execute manifestsTemplate: %v", err)
+	}
+
+	return buf.String()
+}
+
+func TestManifestsTemplateRendersDefaultingWebhookOptions(t *testing.T) {
+	got := renderManifests(t, manifestsTemplateData{
+		Resource: fakeResource{
+			Group: "ship", Version: "v1beta1", Kind: "Frigate", Plural: "frigates",
+			Webhooks: fakeWebhooks{WebhookVersion: "v1"}, defaulting: true,
+		},
+		Options: ManifestOptions{
+			Path:                    "/custom-mutate",
+			FailurePolicy:           "Ignore",
+			SideEffects:             "NoneOnDryRun",
+			TimeoutSeconds:          7,
+			AdmissionReviewVersions: []string{"v1", "v1beta1"},
+		},
+	})
+
+	for _, want := range []string{
+		"kind: MutatingWebhookConfiguration",
+		"path: /custom-mutate",
+		"failurePolicy: Ignore",
+		"sideEffects: NoneOnDryRun",
+		"timeoutSeconds: 7",
+		"- v1\n",
+		"- v1beta1\n",
+		"name: mfrigate.kb.io",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected rendered manifest to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, "ValidatingWebhookConfiguration") {
+		t.Errorf("did not expect a validating webhook entry, got:\n%s", got)
+	}
+}
+
+func TestManifestsTemplateDefaultsPathWhenUnset(t *testing.T) {
+	got := renderManifests(t, manifestsTemplateData{
+		Resource: fakeResource{
+			Group: "ship", Version: "v1beta1", Kind: "Frigate", Plural: "frigates",
+			Webhooks: fakeWebhooks{WebhookVersion: "v1"}, validating: true,
+		},
+		Options: ManifestOptions{
+			FailurePolicy:           "Fail",
+			SideEffects:             "None",
+			TimeoutSeconds:          10,
+			AdmissionReviewVersions: []string{"v1"},
+		},
+	})
+
+	if !strings.Contains(got, "path: /validate-ship-v1beta1-frigate") {
+		t.Errorf("expected the default validating webhook path to be derived from the resource, got:\n%s", got)
+	}
+}
+
+func TestManifestsSetTemplateDefaultsFillsInOptionDefaults(t *testing.T) {
+	m := &Manifests{}
+
+	if err := m.SetTemplateDefaults(); err != nil {
+		t.Fatalf("SetTemplateDefaults() returned an error: %v", err)
+	}
+
+	if m.Options.FailurePolicy != defaultManifestsFailurePolicy {
+		t.Errorf("FailurePolicy = %q, want %q", m.Options.FailurePolicy, defaultManifestsFailurePolicy)
+	}
+	if m.Options.SideEffects != defaultManifestsSideEffects {
+		t.Errorf("SideEffects = %q, want %q", m.Options.SideEffects, defaultManifestsSideEffects)
+	}
+	if m.Options.TimeoutSeconds != defaultManifestsTimeoutSeconds {
+		t.Errorf("TimeoutSeconds = %d, want %d", m.Options.TimeoutSeconds, defaultManifestsTimeoutSeconds)
+	}
+	if len(m.Options.AdmissionReviewVersions) != 1 || m.Options.AdmissionReviewVersions[0] != "v1" {
+		t.Errorf("AdmissionReviewVersions = %v, want [v1]", m.Options.AdmissionReviewVersions)
+	}
+}
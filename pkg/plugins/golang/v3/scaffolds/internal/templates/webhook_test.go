@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// fakeResource stands in for resource.Resource: the real type lives outside this tree,
+// but webhookTemplate only ever touches the fields and methods stubbed here, so this is
+// enough to exercise the rendering logic directly.
+type fakeResource struct {
+	Group, Version, Kind, Plural string
+
+	defaulting, validating, conversion bool
+}
+
+func (r fakeResource) HasDefaultingWebhook() bool { return r.defaulting }
+func (r fakeResource) HasValidationWebhook() bool { return r.validating }
+func (r fakeResource) HasConversionWebhook() bool { return r.conversion }
+
+type webhookTemplateData struct {
+	Boilerplate     string
+	Resource        fakeResource
+	ManifestOptions ManifestOptions
+}
+
+func renderWebhook(t *testing.T, data webhookTemplateData) string {
+	t.Helper()
+
+	tmpl, err := template.New("webhook").Funcs(template.FuncMap{"lower": strings.ToLower}).Parse(webhookTemplate)
+	if err != nil {
+		t.Fatalf("unable to parse webhookTemplate: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		t.Fatalf("unable to execute webhookTemplate: %v", err)
+	}
+
+	return buf.String()
+}
+
+func TestWebhookTemplateAdmissionReviewVersionsHasNoTrailingSeparator(t *testing.T) {
+	got := renderWebhook(t, webhookTemplateData{
+		Resource: fakeResource{Group: "ship", Version: "v1beta1", Kind: "Frigate", Plural: "frigates", defaulting: true},
+		ManifestOptions: ManifestOptions{
+			FailurePolicy: "Fail", SideEffects: "None", TimeoutSeconds: 10,
+			AdmissionReviewVersions: []string{"v1"},
+		},
+	})
+
+	if !strings.Contains(got, "admissionReviewVersions=v1\n") {
+		t.Errorf("expected a single admission review version with no trailing separator, got:\n%s", got)
+	}
+	if strings.Contains(got, "admissionReviewVersions=v1;\n") {
+		t.Errorf("did not expect a trailing ';' after the last admission review version, got:\n%s", got)
+	}
+}
+
+func TestWebhookTemplateAdmissionReviewVersionsJoinsMultipleWithSemicolon(t *testing.T) {
+	got := renderWebhook(t, webhookTemplateData{
+		Resource: fakeResource{Group: "ship", Version: "v1beta1", Kind: "Frigate", Plural: "frigates", defaulting: true},
+		ManifestOptions: ManifestOptions{
+			FailurePolicy: "Fail", SideEffects: "None", TimeoutSeconds: 10,
+			AdmissionReviewVersions: []string{"v1", "v1beta1"},
+		},
+	})
+
+	if !strings.Contains(got, "admissionReviewVersions=v1;v1beta1\n") {
+		t.Errorf("expected admission review versions joined with ';' and no trailing separator, got:\n%s", got)
+	}
+}
+
+func TestWebhookTemplateRegistersCustomPathDirectly(t *testing.T) {
+	got := renderWebhook(t, webhookTemplateData{
+		Resource: fakeResource{Group: "ship", Version: "v1beta1", Kind: "Frigate", Plural: "frigates", defaulting: true},
+		ManifestOptions: ManifestOptions{
+			Path: "/custom-mutate", FailurePolicy: "Ignore", SideEffects: "None", TimeoutSeconds: 10,
+			AdmissionReviewVersions: []string{"v1"},
+		},
+	})
+
+	if !strings.Contains(got, `mgr.GetWebhookServer().Register("/custom-mutate",`) {
+		t.Errorf("expected SetupWebhookWithManager to register the custom path directly, got:\n%s", got)
+	}
+	if strings.Contains(got, "ctrl.NewWebhookManagedBy(mgr).") {
+		t.Errorf("did not expect the default builder to be used once a custom path is set, got:\n%s", got)
+	}
+}
+
+func TestWebhookTemplateRegistersBothHandlersAtSamePathWhenDefaultingAndValidating(t *testing.T) {
+	// The command's Validate() (pkg/plugins/golang/v3/webhook.go validateWebhookPath)
+	// rejects --webhook-path paired with both --defaulting and --programmatic-validation
+	// before this template is ever rendered, because the two registrations below would
+	// collide on the same *http.ServeMux pattern and panic at controller startup. This
+	// test documents that the template itself has no such guard, so that invariant isn't
+	// silently lost if the caller-side check is ever relaxed.
+	got := renderWebhook(t, webhookTemplateData{
+		Resource: fakeResource{
+			Group: "ship", Version: "v1beta1", Kind: "Frigate", Plural: "frigates",
+			defaulting: true, validating: true,
+		},
+		ManifestOptions: ManifestOptions{
+			Path: "/custom-hook", FailurePolicy: "Ignore", SideEffects: "None", TimeoutSeconds: 10,
+			AdmissionReviewVersions: []string{"v1"},
+		},
+	})
+
+	if count := strings.Count(got, `mgr.GetWebhookServer().Register("/custom-hook",`); count != 2 {
+		t.Errorf("expected two registrations at the same literal path, got %d in:\n%s", count, got)
+	}
+}
+
+func TestWebhookTemplateUsesBuilderWhenPathUnset(t *testing.T) {
+	got := renderWebhook(t, webhookTemplateData{
+		Resource: fakeResource{Group: "ship", Version: "v1beta1", Kind: "Frigate", Plural: "frigates", defaulting: true},
+		ManifestOptions: ManifestOptions{
+			FailurePolicy: "Fail", SideEffects: "None", TimeoutSeconds: 10,
+			AdmissionReviewVersions: []string{"v1"},
+		},
+	})
+
+	if !strings.Contains(got, "ctrl.NewWebhookManagedBy(mgr).") {
+		t.Errorf("expected the default builder to be used when no custom path is set, got:\n%s", got)
+	}
+	if strings.Contains(got, "mgr.GetWebhookServer().Register(") {
+		t.Errorf("did not expect direct registration when no custom path is set, got:\n%s", got)
+	}
+}
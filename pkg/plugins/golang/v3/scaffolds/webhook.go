@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds/internal/templates"
+	webhookconfig "sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds/internal/templates/config/webhook"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/internal/cmdutil"
+)
+
+var _ cmdutil.Scaffolder = &webhookScaffolder{}
+
+// WebhookManifestOptions are the admission webhook manifest fields a user can override
+// when scaffolding a webhook, instead of hand-editing the generated
+// {Mutating,Validating}WebhookConfiguration YAML afterwards.
+type WebhookManifestOptions struct {
+	// Path overrides the default /{mutate,validate}-<group>-<version>-<kind> route.
+	Path string
+	// FailurePolicy is the failurePolicy of the scaffolded webhook. Options: [Ignore, Fail].
+	FailurePolicy string
+	// SideEffects is the sideEffects of the scaffolded webhook. Options: [None, NoneOnDryRun, Some, Unknown].
+	SideEffects string
+	// TimeoutSeconds is the timeoutSeconds of the scaffolded webhook.
+	TimeoutSeconds int
+	// AdmissionReviewVersions is the admissionReviewVersions of the scaffolded webhook.
+	AdmissionReviewVersions []string
+}
+
+// webhookScaffolder contains configuration for scaffolding webhook-related manifests and Go files.
+type webhookScaffolder struct {
+	config      config.Config
+	boilerplate string
+	resource    resource.Resource
+	force       bool
+
+	manifestOptions WebhookManifestOptions
+
+	// fs is the filesystem webhookScaffolder writes to. It defaults to the fs passed to
+	// NewWebhookScaffolder (e.g. an in-memory fs for --dry-run), but cmdutil.Run
+	// unconditionally calls InjectFS with an OS filesystem after GetScaffolder returns, so
+	// InjectFS only applies it when fs hasn't already been set explicitly.
+	fs machinery.Filesystem
+}
+
+// NewWebhookScaffolder returns a new Scaffolder for webhook manifests and Go files. fs is
+// the filesystem to scaffold into; pass an in-memory fs (e.g. afero.NewMemMapFs) to preview
+// the scaffold without touching disk.
+func NewWebhookScaffolder(
+	cfg config.Config,
+	boilerplate string,
+	res resource.Resource,
+	force bool,
+	manifestOptions WebhookManifestOptions,
+	fs machinery.Filesystem,
+) cmdutil.Scaffolder {
+	return &webhookScaffolder{
+		config:          cfg,
+		boilerplate:     boilerplate,
+		resource:        res,
+		force:           force,
+		manifestOptions: manifestOptions,
+		fs:              fs,
+	}
+}
+
+// InjectFS implements cmdutil.Scaffolder. cmdutil.Run calls this unconditionally with an OS
+// filesystem after GetScaffolder constructs the scaffolder, which would otherwise clobber a
+// filesystem (e.g. an in-memory one for --dry-run) passed explicitly to NewWebhookScaffolder.
+func (s *webhookScaffolder) InjectFS(fs machinery.Filesystem) {
+	if s.fs.FS != nil {
+		return
+	}
+	s.fs = fs
+}
+
+// Scaffold implements cmdutil.Scaffolder. It scaffolds the webhook Go file (the
+// Default()/ValidateCreate()/.../conversion boilerplate the user fills in) and the
+// config/webhook/manifests.yaml kustomize patch for the resource's webhooks.
+func (s *webhookScaffolder) Scaffold() error {
+	scaffold := machinery.NewScaffold(s.fs,
+		machinery.WithConfig(s.config),
+		machinery.WithBoilerplate(s.boilerplate),
+		machinery.WithResource(&s.resource),
+	)
+
+	templateManifestOptions := webhookconfig.ManifestOptions(s.manifestOptions)
+
+	if err := scaffold.Execute(
+		&templates.Webhook{Force: s.force, ManifestOptions: templates.ManifestOptions(s.manifestOptions)},
+		&webhookconfig.Manifests{Force: s.force, Options: templateManifestOptions},
+	); err != nil {
+		return fmt.Errorf("unable to scaffold webhook: %v", err)
+	}
+
+	return nil
+}
@@ -19,11 +19,14 @@ package v3
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 
+	"github.com/spf13/afero"
 	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
 	goPlugin "sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang"
@@ -34,6 +37,19 @@ import (
 // defaultWebhookVersion is the default mutating/validating webhook config API version to scaffold.
 const defaultWebhookVersion = "v1"
 
+// defaultWebhookTimeoutSeconds is the default admission webhook timeout scaffolded when
+// --timeout-seconds is not provided.
+const defaultWebhookTimeoutSeconds = 10
+
+// validFailurePolicies, validSideEffects and validWebhookVersions mirror the enums
+// accepted by the admissionregistration.k8s.io API so we can fail fast in Validate()
+// instead of letting the apiserver reject the generated manifest later.
+var (
+	validFailurePolicies = []string{"Ignore", "Fail"}
+	validSideEffects     = []string{"None", "NoneOnDryRun", "Some", "Unknown"}
+	validWebhookVersions = []string{"v1", "v1beta1"}
+)
+
 type createWebhookSubcommand struct {
 	config config.Config
 	// For help text.
@@ -45,6 +61,34 @@ type createWebhookSubcommand struct {
 
 	// force indicates that the resource should be created even if it already exists
 	force bool
+
+	// webhookPath overrides the default path the manifests route admission requests to.
+	webhookPath string
+	// failurePolicy is the failurePolicy set on the scaffolded {Mutating,Validating}WebhookConfigurations.
+	failurePolicy string
+	// sideEffects is the sideEffects set on the scaffolded {Mutating,Validating}WebhookConfigurations.
+	sideEffects string
+	// timeoutSeconds is the timeoutSeconds set on the scaffolded {Mutating,Validating}WebhookConfigurations.
+	timeoutSeconds int
+	// admissionReviewVersions is the admissionReviewVersions set on the scaffolded
+	// {Mutating,Validating}WebhookConfigurations.
+	admissionReviewVersions []string
+
+	// dryRun indicates that the webhook should be scaffolded into an in-memory filesystem
+	// and the result printed as a diff (or copied to outputDir) instead of written in place.
+	//
+	// Note: because the scaffold runs against an in-memory fs, the already-exists guard in
+	// machinery.IfExistsAction (see templates.Webhook/config/webhook.Manifests
+	// SetTemplateDefaults) never fires here regardless of what --force would do on a real
+	// run, so a clean dry-run preview does not guarantee the same scaffold would succeed
+	// without --force.
+	dryRun bool
+	// outputDir, if set alongside dryRun, copies the scaffolded files there instead of
+	// printing a diff.
+	outputDir string
+	// fs is the filesystem scaffolding is rendered into: the OS filesystem normally, or an
+	// in-memory filesystem when dryRun is set.
+	fs afero.Fs
 }
 
 var (
@@ -62,8 +106,15 @@ validating and (or) conversion webhooks.
 
   # Create conversion webhook for CRD of group ship, version v1beta1 and kind Frigate.
   %s create webhook --group ship --version v1beta1 --kind Frigate --conversion
+
+  # Create defaulting webhook with a custom path, failure policy and timeout.
+  %s create webhook --group ship --version v1beta1 --kind Frigate --defaulting \
+    --webhook-path /custom-mutate --failure-policy Ignore --timeout-seconds 5
+
+  # Preview the webhook scaffold as a diff without writing any files.
+  %s create webhook --group ship --version v1beta1 --kind Frigate --defaulting --dry-run
 `,
-		ctx.CommandName, ctx.CommandName)
+		ctx.CommandName, ctx.CommandName, ctx.CommandName, ctx.CommandName)
 
 	p.commandName = ctx.CommandName
 }
@@ -85,8 +136,26 @@ func (p *createWebhookSubcommand) BindFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&p.options.DoConversion, "conversion", false,
 		"if set, scaffold the conversion webhook")
 
+	fs.StringVar(&p.webhookPath, "webhook-path", "",
+		"path the manifests route admission requests to, defaults to a path derived from the resource; "+
+			"cannot be used with both --defaulting and --programmatic-validation")
+	fs.StringVar(&p.failurePolicy, "failure-policy", "Fail",
+		"failurePolicy to scaffold for the admission webhooks. Options: [Ignore, Fail]")
+	fs.StringVar(&p.sideEffects, "side-effects", "None",
+		"sideEffects to scaffold for the admission webhooks. Options: [None, NoneOnDryRun, Some, Unknown]")
+	fs.IntVar(&p.timeoutSeconds, "timeout-seconds", defaultWebhookTimeoutSeconds,
+		"timeoutSeconds to scaffold for the admission webhooks, must be between 1 and 30")
+	fs.StringSliceVar(&p.admissionReviewVersions, "admission-review-versions", []string{"v1"},
+		"admissionReviewVersions to scaffold for the admission webhooks")
+
 	fs.BoolVar(&p.force, "force", false,
 		"attempt to create resource even if it already exists")
+
+	fs.BoolVar(&p.dryRun, "dry-run", false,
+		"preview the webhook scaffold as a diff instead of writing files (does not check "+
+			"whether the scaffold would fail with \"already exists\" on a real run)")
+	fs.StringVar(&p.outputDir, "output-dir", "",
+		"when used with --dry-run, copy the scaffolded files here instead of printing a diff")
 }
 
 func (p *createWebhookSubcommand) InjectConfig(c config.Config) {
@@ -126,9 +195,74 @@ func (p *createWebhookSubcommand) Validate() error {
 			p.resource.Webhooks.WebhookVersion)
 	}
 
+	if p.outputDir != "" && !p.dryRun {
+		return fmt.Errorf("--output-dir can only be used together with --dry-run")
+	}
+
+	if err := validateWebhookPath(p.webhookPath, p.options.DoDefaulting, p.options.DoValidation); err != nil {
+		return err
+	}
+
+	return validateManifestOptions(p.failurePolicy, p.sideEffects, p.timeoutSeconds, p.admissionReviewVersions)
+}
+
+// validateWebhookPath rejects a --webhook-path paired with both --defaulting and
+// --programmatic-validation: SetupWebhookWithManager registers each handler at that same
+// literal path, and webhook.Server.Register panics on the resulting duplicate
+// *http.ServeMux pattern. It is independent of config/resource state so it can be
+// exercised directly in unit tests.
+func validateWebhookPath(webhookPath string, doDefaulting, doValidation bool) error {
+	if webhookPath != "" && doDefaulting && doValidation {
+		return fmt.Errorf("--webhook-path cannot be used with both --defaulting and" +
+			" --programmatic-validation: both webhooks would be registered at the same path")
+	}
+
+	return nil
+}
+
+// validateManifestOptions validates the --failure-policy, --side-effects, --timeout-seconds and
+// --admission-review-versions flags. It is independent of config/resource state so it can be
+// exercised directly in unit tests.
+func validateManifestOptions(
+	failurePolicy, sideEffects string, timeoutSeconds int, admissionReviewVersions []string,
+) error {
+	if !stringSliceContains(validFailurePolicies, failurePolicy) {
+		return fmt.Errorf("invalid value for --failure-policy: %q, must be one of %v",
+			failurePolicy, validFailurePolicies)
+	}
+
+	if !stringSliceContains(validSideEffects, sideEffects) {
+		return fmt.Errorf("invalid value for --side-effects: %q, must be one of %v",
+			sideEffects, validSideEffects)
+	}
+
+	if timeoutSeconds < 1 || timeoutSeconds > 30 {
+		return fmt.Errorf("invalid value for --timeout-seconds: %d, must be between 1 and 30", timeoutSeconds)
+	}
+
+	if len(admissionReviewVersions) == 0 {
+		return fmt.Errorf("--admission-review-versions must not be empty")
+	}
+	for _, version := range admissionReviewVersions {
+		if !stringSliceContains(validWebhookVersions, version) {
+			return fmt.Errorf("invalid value for --admission-review-versions: %q, must be one of %v",
+				version, validWebhookVersions)
+		}
+	}
+
 	return nil
 }
 
+// stringSliceContains returns true if s is present in slice.
+func stringSliceContains(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *createWebhookSubcommand) GetScaffolder() (cmdutil.Scaffolder, error) {
 	// Load the boilerplate
 	bp, err := ioutil.ReadFile(filepath.Join("hack", "boilerplate.go.txt")) // nolint:gosec
@@ -136,9 +270,105 @@ func (p *createWebhookSubcommand) GetScaffolder() (cmdutil.Scaffolder, error) {
 		return nil, fmt.Errorf("unable to load boilerplate: %v", err)
 	}
 
-	return scaffolds.NewWebhookScaffolder(p.config, string(bp), p.resource, p.force), nil
+	if p.dryRun {
+		p.fs = afero.NewMemMapFs()
+	} else {
+		p.fs = afero.NewOsFs()
+	}
+
+	return scaffolds.NewWebhookScaffolder(p.config, string(bp), p.resource, p.force,
+		scaffolds.WebhookManifestOptions{
+			Path:                    p.webhookPath,
+			FailurePolicy:           p.failurePolicy,
+			SideEffects:             p.sideEffects,
+			TimeoutSeconds:          p.timeoutSeconds,
+			AdmissionReviewVersions: p.admissionReviewVersions,
+		},
+		machinery.Filesystem{FS: p.fs}), nil
 }
 
 func (p *createWebhookSubcommand) PostScaffold() error {
-	return nil
+	if !p.dryRun {
+		return nil
+	}
+
+	if p.outputDir != "" {
+		return copyFs(p.fs, afero.NewOsFs(), p.outputDir)
+	}
+
+	return printDiff(p.fs)
+}
+
+// copyFs copies every file under src to dst, rooted at destDir.
+func copyFs(src, dst afero.Fs, destDir string) error {
+	return afero.Walk(src, ".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		content, err := afero.ReadFile(src, path)
+		if err != nil {
+			return fmt.Errorf("unable to read %q: %v", path, err)
+		}
+
+		destPath := filepath.Join(destDir, path)
+		if err := dst.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+			return fmt.Errorf("unable to create %q: %v", filepath.Dir(destPath), err)
+		}
+		if err := afero.WriteFile(dst, destPath, content, 0o644); err != nil {
+			return fmt.Errorf("unable to write %q: %v", destPath, err)
+		}
+
+		return nil
+	})
+}
+
+// printDiff prints a unified diff of every file scaffolded into fs against what's currently
+// on disk (or against an empty file, if it doesn't exist yet).
+func printDiff(fs afero.Fs) error {
+	osFs := afero.NewOsFs()
+
+	return afero.Walk(fs, ".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		newContent, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return fmt.Errorf("unable to read %q: %v", path, err)
+		}
+
+		var oldContent []byte
+		exists, err := afero.Exists(osFs, path)
+		if err != nil {
+			return fmt.Errorf("unable to stat %q: %v", path, err)
+		}
+		if exists {
+			if oldContent, err = afero.ReadFile(osFs, path); err != nil {
+				return fmt.Errorf("unable to read %q: %v", path, err)
+			}
+		}
+
+		hunks := computeHunks(diffLines(splitLines(oldContent), splitLines(newContent)))
+		if len(hunks) == 0 {
+			return nil
+		}
+
+		oldFile := fmt.Sprintf("a/%s", path)
+		if !exists {
+			oldFile = "/dev/null"
+		}
+		fmt.Printf("--- %s\n+++ b/%s\n", oldFile, path)
+		for _, h := range hunks {
+			fmt.Print(formatHunk(h))
+		}
+
+		return nil
+	})
 }
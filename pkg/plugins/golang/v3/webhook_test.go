@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestStringSliceContains(t *testing.T) {
+	slice := []string{"Ignore", "Fail"}
+
+	if !stringSliceContains(slice, "Fail") {
+		t.Errorf("expected slice to contain %q", "Fail")
+	}
+
+	if stringSliceContains(slice, "Unknown") {
+		t.Errorf("did not expect slice to contain %q", "Unknown")
+	}
+}
+
+func TestValidateManifestOptions(t *testing.T) {
+	cases := []struct {
+		name                    string
+		failurePolicy           string
+		sideEffects             string
+		timeoutSeconds          int
+		admissionReviewVersions []string
+		wantErr                 bool
+	}{
+		{
+			name: "valid", failurePolicy: "Fail", sideEffects: "None", timeoutSeconds: 10,
+			admissionReviewVersions: []string{"v1"}, wantErr: false,
+		},
+		{
+			name: "valid with alternate enum values", failurePolicy: "Ignore", sideEffects: "NoneOnDryRun",
+			timeoutSeconds: 30, admissionReviewVersions: []string{"v1", "v1beta1"}, wantErr: false,
+		},
+		{
+			name: "invalid failure policy", failurePolicy: "Retry", sideEffects: "None", timeoutSeconds: 10,
+			admissionReviewVersions: []string{"v1"}, wantErr: true,
+		},
+		{
+			name: "invalid side effects", failurePolicy: "Fail", sideEffects: "Everything", timeoutSeconds: 10,
+			admissionReviewVersions: []string{"v1"}, wantErr: true,
+		},
+		{
+			name: "timeout too low", failurePolicy: "Fail", sideEffects: "None", timeoutSeconds: 0,
+			admissionReviewVersions: []string{"v1"}, wantErr: true,
+		},
+		{
+			name: "timeout too high", failurePolicy: "Fail", sideEffects: "None", timeoutSeconds: 31,
+			admissionReviewVersions: []string{"v1"}, wantErr: true,
+		},
+		{
+			name: "empty admission review versions", failurePolicy: "Fail", sideEffects: "None", timeoutSeconds: 10,
+			admissionReviewVersions: nil, wantErr: true,
+		},
+		{
+			name: "invalid admission review version", failurePolicy: "Fail", sideEffects: "None", timeoutSeconds: 10,
+			admissionReviewVersions: []string{"v2"}, wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateManifestOptions(tc.failurePolicy, tc.sideEffects, tc.timeoutSeconds, tc.admissionReviewVersions)
+			if tc.wantErr && err == nil {
+				t.Errorf("validateManifestOptions() = nil, want an error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("validateManifestOptions() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidateWebhookPath(t *testing.T) {
+	cases := []struct {
+		name                       string
+		webhookPath                string
+		doDefaulting, doValidation bool
+		wantErr                    bool
+	}{
+		{name: "no path set", webhookPath: "", doDefaulting: true, doValidation: true, wantErr: false},
+		{name: "path with defaulting only", webhookPath: "/custom-hook", doDefaulting: true, wantErr: false},
+		{name: "path with validation only", webhookPath: "/custom-hook", doValidation: true, wantErr: false},
+		{
+			name: "path with both defaulting and validation", webhookPath: "/custom-hook",
+			doDefaulting: true, doValidation: true, wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateWebhookPath(tc.webhookPath, tc.doDefaulting, tc.doValidation)
+			if tc.wantErr && err == nil {
+				t.Errorf("validateWebhookPath() = nil, want an error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("validateWebhookPath() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestCopyFsCopiesFilesIntoDestDir(t *testing.T) {
+	src := afero.NewMemMapFs()
+	if err := afero.WriteFile(src, "api/v1/frigate_webhook.go", []byte("package v1\n"), 0o644); err != nil {
+		t.Fatalf("unable to seed src fs: %v", err)
+	}
+
+	dst := afero.NewMemMapFs()
+	if err := copyFs(src, dst, "/out"); err != nil {
+		t.Fatalf("copyFs() returned an error: %v", err)
+	}
+
+	got, err := afero.ReadFile(dst, "/out/api/v1/frigate_webhook.go")
+	if err != nil {
+		t.Fatalf("expected copied file to exist: %v", err)
+	}
+	if string(got) != "package v1\n" {
+		t.Errorf("copied content = %q, want %q", got, "package v1\n")
+	}
+}
+
+func TestPrintDiffWalksScaffoldedFilesWithoutError(t *testing.T) {
+	// printDiff always compares against the real OS filesystem, so there is no way from
+	// here to make a scaffolded file "identical" to what's on disk; this only exercises
+	// the walk/read/hunk-printing path for a file that doesn't exist on disk yet.
+	// computeHunks/diffLines (the diffing core, including the identical-input no-op case)
+	// have their own tests in diff_test.go.
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "config/webhook/manifests.yaml", []byte("a\nb\nc\n"), 0o644); err != nil {
+		t.Fatalf("unable to seed fs: %v", err)
+	}
+
+	if err := printDiff(fs); err != nil {
+		t.Errorf("printDiff() returned an error: %v", err)
+	}
+}
+
+func TestPrintDiffUsesDevNullForNewFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	if err := afero.WriteFile(fs, "api/v1/frigate_webhook.go", []byte("package v1\n"), 0o644); err != nil {
+		t.Fatalf("unable to seed fs: %v", err)
+	}
+
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("unable to create pipe: %v", err)
+	}
+	os.Stdout = w
+
+	printErr := printDiff(fs)
+
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		t.Fatalf("unable to read captured output: %v", err)
+	}
+	got := buf.String()
+
+	if printErr != nil {
+		t.Fatalf("printDiff() returned an error: %v", printErr)
+	}
+	if !strings.Contains(got, "--- /dev/null\n") {
+		t.Errorf("expected the old-file marker for a brand-new file to be /dev/null, got:\n%s", got)
+	}
+	if !strings.Contains(got, "@@ -0,0 +1,1 @@\n") {
+		t.Errorf("expected a new-file hunk header starting at 0,0, got:\n%s", got)
+	}
+}